@@ -0,0 +1,264 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// Accumulator computes running count, mean, variance, min, max and
+// (optionally) quantile estimates from a stream of values, without
+// retaining the values themselves. This makes it suitable for
+// summarizing streams too large to keep as a Sample.
+//
+// The zero Accumulator is ready to use, with no quantile tracking.
+// Use NewAccumulator to track quantiles.
+type Accumulator struct {
+	count  int64
+	weight float64
+	mean   float64
+	m2     float64 // West's running weighted sum of squared deviations (T).
+	min    float64
+	max    float64
+
+	quantiles map[float64]*p2Quantile
+}
+
+// NewAccumulator returns an Accumulator that, in addition to count,
+// mean and variance, maintains a streaming estimate of each
+// quantile in ps (for example, 0.5 for the median) using the P²
+// algorithm of Jain & Chlamtac.
+func NewAccumulator(ps ...float64) *Accumulator {
+	a := &Accumulator{min: math.Inf(1), max: math.Inf(-1)}
+	if len(ps) > 0 {
+		a.quantiles = make(map[float64]*p2Quantile, len(ps))
+		for _, p := range ps {
+			a.quantiles[p] = &p2Quantile{p: p}
+		}
+	}
+	return a
+}
+
+// Push adds x to the accumulator with weight 1.
+func (a *Accumulator) Push(x float64) {
+	a.PushWeighted(x, 1)
+}
+
+// PushWeighted adds x to the accumulator with weight w. Points with
+// w <= 0 are ignored.
+func (a *Accumulator) PushWeighted(x, w float64) {
+	if w <= 0 {
+		return
+	}
+	if a.count == 0 {
+		a.min, a.max = x, x
+	} else {
+		if x < a.min {
+			a.min = x
+		}
+		if x > a.max {
+			a.max = x
+		}
+	}
+	a.count++
+
+	// West's (1979) incremental update, as used by
+	// Sample.WeightedVariance.
+	Wnext := a.weight + w
+	Q := x - a.mean
+	R := Q * w / Wnext
+	a.mean += R
+	a.m2 += a.weight * Q * R
+	a.weight = Wnext
+
+	for _, e := range a.quantiles {
+		e.push(x)
+	}
+}
+
+// Count returns the number of points pushed to a.
+func (a *Accumulator) Count() int64 { return a.count }
+
+// Weight returns the total weight of the points pushed to a.
+func (a *Accumulator) Weight() float64 { return a.weight }
+
+// Mean returns the (possibly weighted) mean of the points pushed to
+// a, or NaN if a is empty.
+func (a *Accumulator) Mean() float64 {
+	if a.count == 0 {
+		return math.NaN()
+	}
+	return a.mean
+}
+
+// Variance returns the frequency-weighted sample variance of the
+// points pushed to a, or NaN if fewer than two effective
+// observations have been pushed.
+func (a *Accumulator) Variance() float64 {
+	if a.weight < 2 {
+		return math.NaN()
+	}
+	return a.m2 / (a.weight - 1)
+}
+
+// StdDev returns the sample standard deviation of the points pushed
+// to a.
+func (a *Accumulator) StdDev() float64 {
+	return math.Sqrt(a.Variance())
+}
+
+// Min returns the minimum value pushed to a, or NaN if a is empty.
+func (a *Accumulator) Min() float64 {
+	if a.count == 0 {
+		return math.NaN()
+	}
+	return a.min
+}
+
+// Max returns the maximum value pushed to a, or NaN if a is empty.
+func (a *Accumulator) Max() float64 {
+	if a.count == 0 {
+		return math.NaN()
+	}
+	return a.max
+}
+
+// Quantile returns the P²-estimated value at quantile p, where p
+// was passed to NewAccumulator. It returns NaN if p was not
+// registered or a is empty.
+func (a *Accumulator) Quantile(p float64) float64 {
+	e, ok := a.quantiles[p]
+	if !ok {
+		return math.NaN()
+	}
+	return e.value()
+}
+
+// Merge folds other into a, as though every point pushed to other
+// had instead been pushed to a, using Chan et al.'s formula for
+// combining partial variances. Quantile estimates, which the P²
+// algorithm does not support merging, are discarded, unless other
+// is empty, in which case a is left unchanged.
+func (a *Accumulator) Merge(other *Accumulator) {
+	if other.count == 0 {
+		return
+	}
+	if a.count == 0 {
+		*a = *other
+		a.quantiles = nil
+		return
+	}
+
+	Wab := a.weight + other.weight
+	delta := other.mean - a.mean
+	a.mean += delta * other.weight / Wab
+	a.m2 += other.m2 + delta*delta*a.weight*other.weight/Wab
+	a.weight = Wab
+	a.count += other.count
+	if other.min < a.min {
+		a.min = other.min
+	}
+	if other.max > a.max {
+		a.max = other.max
+	}
+	a.quantiles = nil
+}
+
+// p2Quantile maintains a streaming estimate of a single quantile
+// using the P² ("piecewise-parabolic") algorithm of Jain & Chlamtac
+// (1985): five markers track the minimum, the maximum, the
+// estimated quantile, and two markers bracketing it, and are
+// adjusted by a parabolic (falling back to linear) prediction as
+// each new observation arrives.
+type p2Quantile struct {
+	p     float64
+	count int
+
+	// n, npos and q are 1-indexed conceptually but stored
+	// 0-indexed: n[i] is the current position of marker i, npos[i]
+	// is its desired (fractional) position, and q[i] is its height
+	// (the estimated value at that position).
+	n    [5]float64
+	npos [5]float64
+	dn   [5]float64
+	q    [5]float64
+}
+
+func (e *p2Quantile) push(x float64) {
+	e.count++
+	if e.count <= 5 {
+		e.q[e.count-1] = x
+		if e.count == 5 {
+			sort.Float64s(e.q[:])
+			for i := range e.n {
+				e.n[i] = float64(i + 1)
+			}
+			e.npos = [5]float64{1, 1 + 2*e.p, 1 + 4*e.p, 3 + 2*e.p, 5}
+			e.dn = [5]float64{0, e.p / 2, e.p, (1 + e.p) / 2, 1}
+		}
+		return
+	}
+
+	k := 0
+	switch {
+	case x < e.q[0]:
+		e.q[0] = x
+	case x >= e.q[4]:
+		e.q[4] = x
+		k = 3
+	default:
+		for i := 0; i < 4; i++ {
+			if e.q[i] <= x && x < e.q[i+1] {
+				k = i
+				break
+			}
+		}
+	}
+
+	for i := k + 1; i < 5; i++ {
+		e.n[i]++
+	}
+	for i := range e.npos {
+		e.npos[i] += e.dn[i]
+	}
+
+	for i := 1; i <= 3; i++ {
+		d := e.npos[i] - e.n[i]
+		if d >= 1 && e.n[i+1]-e.n[i] > 1 {
+			e.adjust(i, 1)
+		} else if d <= -1 && e.n[i-1]-e.n[i] < -1 {
+			e.adjust(i, -1)
+		}
+	}
+}
+
+// adjust moves marker i by d (+1 or -1), using the P² parabolic
+// prediction formula if it keeps the markers ordered, or a linear
+// prediction otherwise.
+func (e *p2Quantile) adjust(i int, d float64) {
+	qp := e.q[i] + d/(e.n[i+1]-e.n[i-1])*((e.n[i]-e.n[i-1]+d)*(e.q[i+1]-e.q[i])/(e.n[i+1]-e.n[i])+
+		(e.n[i+1]-e.n[i]-d)*(e.q[i]-e.q[i-1])/(e.n[i]-e.n[i-1]))
+	if e.q[i-1] < qp && qp < e.q[i+1] {
+		e.q[i] = qp
+	} else {
+		di := int(d)
+		e.q[i] += d * (e.q[i+di] - e.q[i]) / (e.n[i+di] - e.n[i])
+	}
+	e.n[i] += d
+}
+
+// value returns the current estimate of the quantile.
+func (e *p2Quantile) value() float64 {
+	if e.count == 0 {
+		return math.NaN()
+	}
+	if e.count < 5 {
+		tmp := append([]float64(nil), e.q[:e.count]...)
+		sort.Float64s(tmp)
+		return tmp[int(e.p*float64(len(tmp)-1))]
+	}
+	return e.q[2]
+}
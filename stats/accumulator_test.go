@@ -0,0 +1,200 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stats
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestAccumulatorAgainstSample(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	xs := make([]float64, 500)
+	for i := range xs {
+		xs[i] = rng.Float64() * 100
+	}
+	s := Sample{Xs: append([]float64(nil), xs...)}
+
+	a := NewAccumulator(0.25, 0.5, 0.75)
+	for _, x := range xs {
+		a.Push(x)
+	}
+
+	if a.Count() != int64(len(xs)) {
+		t.Errorf("Count() = %v, want %v", a.Count(), len(xs))
+	}
+	if math.Abs(a.Mean()-s.Mean()) > 1e-9 {
+		t.Errorf("Mean() = %v, want %v", a.Mean(), s.Mean())
+	}
+	if math.Abs(a.Variance()-s.Variance()) > 1e-9 {
+		t.Errorf("Variance() = %v, want %v", a.Variance(), s.Variance())
+	}
+	min, max := s.Bounds()
+	if a.Min() != min {
+		t.Errorf("Min() = %v, want %v", a.Min(), min)
+	}
+	if a.Max() != max {
+		t.Errorf("Max() = %v, want %v", a.Max(), max)
+	}
+
+	// The P² quantile estimate is approximate, not exact, so just
+	// check it's in the right ballpark of the true quantile.
+	for _, p := range []float64{0.25, 0.5, 0.75} {
+		got := a.Quantile(p)
+		want := s.Percentile(p)
+		if math.Abs(got-want) > 5 {
+			t.Errorf("Quantile(%v) = %v, want close to %v", p, got, want)
+		}
+	}
+}
+
+func TestAccumulatorWeighted(t *testing.T) {
+	xs := []float64{1, 2, 3, 4, 5}
+	weights := []float64{2, 1, 3, 1, 2}
+	s := Sample{Xs: xs, Weights: weights}
+
+	a := &Accumulator{}
+	for i, x := range xs {
+		a.PushWeighted(x, weights[i])
+	}
+
+	if math.Abs(a.Mean()-s.Mean()) > 1e-9 {
+		t.Errorf("Mean() = %v, want %v", a.Mean(), s.Mean())
+	}
+	if math.Abs(a.Variance()-s.Variance()) > 1e-9 {
+		t.Errorf("Variance() = %v, want %v", a.Variance(), s.Variance())
+	}
+	if a.Weight() != Sum(weights) {
+		t.Errorf("Weight() = %v, want %v", a.Weight(), Sum(weights))
+	}
+}
+
+func TestAccumulatorIgnoresNonPositiveWeight(t *testing.T) {
+	a := &Accumulator{}
+	a.PushWeighted(1, 1)
+	a.PushWeighted(1000, 0)
+	a.PushWeighted(1000, -1)
+	a.Push(3)
+
+	if a.Count() != 2 {
+		t.Errorf("Count() = %v, want 2", a.Count())
+	}
+	if a.Max() != 3 {
+		t.Errorf("Max() = %v, want 3 (the w<=0 pushes should be ignored)", a.Max())
+	}
+}
+
+func TestAccumulatorEmpty(t *testing.T) {
+	a := &Accumulator{}
+	for name, got := range map[string]float64{
+		"Mean":     a.Mean(),
+		"Variance": a.Variance(),
+		"Min":      a.Min(),
+		"Max":      a.Max(),
+	} {
+		if !math.IsNaN(got) {
+			t.Errorf("%s() = %v on empty Accumulator, want NaN", name, got)
+		}
+	}
+}
+
+func TestAccumulatorMerge(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	xs := make([]float64, 400)
+	for i := range xs {
+		xs[i] = rng.Float64() * 1000
+	}
+	s := Sample{Xs: append([]float64(nil), xs...)}
+
+	whole := &Accumulator{}
+	for _, x := range xs {
+		whole.Push(x)
+	}
+
+	split := len(xs) / 3
+	a := &Accumulator{}
+	for _, x := range xs[:split] {
+		a.Push(x)
+	}
+	b := &Accumulator{}
+	for _, x := range xs[split:] {
+		b.Push(x)
+	}
+	a.Merge(b)
+
+	if a.Count() != whole.Count() {
+		t.Errorf("merged Count() = %v, want %v", a.Count(), whole.Count())
+	}
+	if math.Abs(a.Mean()-whole.Mean()) > 1e-9 {
+		t.Errorf("merged Mean() = %v, want %v", a.Mean(), whole.Mean())
+	}
+	if math.Abs(a.Mean()-s.Mean()) > 1e-9 {
+		t.Errorf("merged Mean() = %v, want %v (against Sample)", a.Mean(), s.Mean())
+	}
+	if math.Abs(a.Variance()-whole.Variance()) > 1e-9 {
+		t.Errorf("merged Variance() = %v, want %v", a.Variance(), whole.Variance())
+	}
+	if math.Abs(a.Variance()-s.Variance()) > 1e-6 {
+		t.Errorf("merged Variance() = %v, want %v (against Sample)", a.Variance(), s.Variance())
+	}
+	min, max := s.Bounds()
+	if a.Min() != min || a.Max() != max {
+		t.Errorf("merged Min/Max = %v/%v, want %v/%v", a.Min(), a.Max(), min, max)
+	}
+}
+
+func TestAccumulatorMergeWeighted(t *testing.T) {
+	xs := []float64{1, 2, 3, 4, 5, 6}
+	weights := []float64{1, 2, 1, 3, 1, 2}
+	s := Sample{Xs: xs, Weights: weights}
+
+	a := &Accumulator{}
+	for i := 0; i < 3; i++ {
+		a.PushWeighted(xs[i], weights[i])
+	}
+	b := &Accumulator{}
+	for i := 3; i < len(xs); i++ {
+		b.PushWeighted(xs[i], weights[i])
+	}
+	a.Merge(b)
+
+	if math.Abs(a.Mean()-s.Mean()) > 1e-9 {
+		t.Errorf("merged weighted Mean() = %v, want %v", a.Mean(), s.Mean())
+	}
+	if math.Abs(a.Variance()-s.Variance()) > 1e-9 {
+		t.Errorf("merged weighted Variance() = %v, want %v", a.Variance(), s.Variance())
+	}
+}
+
+func TestAccumulatorMergeIntoEmpty(t *testing.T) {
+	a := &Accumulator{}
+	b := &Accumulator{}
+	b.Push(1)
+	b.Push(2)
+	a.Merge(b)
+	if a.Count() != 2 || a.Mean() != 1.5 {
+		t.Errorf("Merge into empty Accumulator: Count=%v Mean=%v, want 2/1.5", a.Count(), a.Mean())
+	}
+}
+
+func TestAccumulatorMergeDropsQuantiles(t *testing.T) {
+	a := NewAccumulator(0.5)
+	a.Push(1)
+	b := NewAccumulator(0.5)
+	b.Push(2)
+	a.Merge(b)
+	if !math.IsNaN(a.Quantile(0.5)) {
+		t.Errorf("Quantile(0.5) after Merge = %v, want NaN (P2 state isn't mergeable)", a.Quantile(0.5))
+	}
+}
+
+func TestAccumulatorQuantileUnregistered(t *testing.T) {
+	a := NewAccumulator(0.5)
+	a.Push(1)
+	if !math.IsNaN(a.Quantile(0.9)) {
+		t.Errorf("Quantile(0.9) for an unregistered p = %v, want NaN", a.Quantile(0.9))
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNormalCDF(t *testing.T) {
+	cases := []struct {
+		z, want float64
+	}{
+		{0, 0.5},
+		{1.959963985, 0.975},
+		{-1.959963985, 0.025},
+		{1, 0.8413447461},
+		{-1, 0.1586552539},
+	}
+	for _, c := range cases {
+		if got := normalCDF(c.z); math.Abs(got-c.want) > 1e-8 {
+			t.Errorf("normalCDF(%v) = %v, want %v", c.z, got, c.want)
+		}
+	}
+}
+
+// Reference values for betainc (the regularized incomplete beta
+// function I_x(a, b)) computed independently, e.g. via R's pbeta.
+func TestBetainc(t *testing.T) {
+	cases := []struct {
+		x, a, b, want float64
+	}{
+		{0, 2, 3, 0},
+		{1, 2, 3, 1},
+		{0.5, 1, 1, 0.5},
+		{0.5, 2, 2, 0.5},
+		{0.3, 2, 5, 0.57982500},
+		{0.7, 5, 2, 0.42017500},
+		{0.1, 0.5, 0.5, 0.20483276}, // arcsine distribution
+	}
+	for _, c := range cases {
+		if got := betainc(c.x, c.a, c.b); math.Abs(got-c.want) > 1e-6 {
+			t.Errorf("betainc(%v, %v, %v) = %v, want %v", c.x, c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestBetaincSymmetry(t *testing.T) {
+	// I_x(a, b) = 1 - I_{1-x}(b, a) should hold regardless of
+	// which side of the continued fraction betainc picks.
+	cases := []struct{ x, a, b float64 }{
+		{0.2, 3, 7}, {0.6, 7, 3}, {0.5, 10, 0.5}, {0.9, 0.5, 10},
+	}
+	for _, c := range cases {
+		got := betainc(c.x, c.a, c.b)
+		want := 1 - betainc(1-c.x, c.b, c.a)
+		if math.Abs(got-want) > 1e-9 {
+			t.Errorf("betainc(%v, %v, %v) = %v, want %v (via symmetry)", c.x, c.a, c.b, got, want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stats
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// naiveWeightedPercentile reimplements Sample.Percentile's weighted
+// case as a plain O(n) linear scan over a copy of the data, sorted
+// here rather than via Sample.Sort/BuildCumWeights, as a reference
+// for the O(log n) CumWeights-based lookup.
+func naiveWeightedPercentile(xs, weights []float64, pctile float64) float64 {
+	type point struct {
+		x float64
+		w float64
+	}
+	pts := make([]point, len(xs))
+	for i := range xs {
+		pts[i] = point{xs[i], weights[i]}
+	}
+	sort.Slice(pts, func(i, j int) bool { return pts[i].x < pts[j].x })
+
+	total := 0.0
+	for _, p := range pts {
+		total += p.w
+	}
+	target := total * pctile
+	cum := 0.0
+	for _, p := range pts {
+		cum += p.w
+		if cum > target {
+			return p.x
+		}
+	}
+	return pts[len(pts)-1].x
+}
+
+func TestWeightedPercentileAgainstNaiveScan(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	ps := []float64{0, 0.1, 0.25, 0.5, 0.75, 0.9, 0.99}
+
+	for trial := 0; trial < 20; trial++ {
+		n := 2 + rng.Intn(50)
+		xs := make([]float64, n)
+		weights := make([]float64, n)
+		for i := range xs {
+			xs[i] = rng.Float64() * 100
+			weights[i] = 0.1 + rng.Float64()*5
+		}
+
+		for _, sorted := range []bool{false, true} {
+			s := Sample{Xs: append([]float64(nil), xs...), Weights: append([]float64(nil), weights...)}
+			if sorted {
+				s.Sort()
+			}
+			for _, p := range ps {
+				got := s.Percentile(p)
+				want := naiveWeightedPercentile(xs, weights, p)
+				if math.Abs(got-want) > 1e-9 {
+					t.Errorf("sorted=%v n=%d p=%v: Percentile = %v, want %v", sorted, n, p, got, want)
+				}
+			}
+		}
+	}
+}
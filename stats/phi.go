@@ -0,0 +1,151 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stats
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// PhiCDF selects the distribution PhiDetector uses to model
+// inter-arrival times when computing phi.
+type PhiCDF int
+
+const (
+	// PhiNormalCDF models inter-arrival times as normally
+	// distributed, using the window's mean and standard
+	// deviation.
+	PhiNormalCDF PhiCDF = iota
+
+	// PhiEmpiricalCDF looks up the elapsed time directly in the
+	// sorted window of past inter-arrival times, making no
+	// assumption about their distribution.
+	PhiEmpiricalCDF
+)
+
+// DefaultSuspicionThreshold is a commonly used phi threshold above
+// which a node is considered likely to have failed; see Suspect.
+const DefaultSuspicionThreshold = 8.0
+
+// defaultPhiWindow is the default size of the sliding window of
+// inter-arrival times.
+const defaultPhiWindow = 1000
+
+// PhiDetector is a "phi accrual" failure detector (Hayashibara et
+// al. 2004): rather than a binary alive/dead verdict, it reports a
+// suspicion level phi that grows smoothly as a heartbeat becomes
+// overdue relative to the recent history of inter-arrival times.
+//
+// A PhiDetector is not safe for concurrent use.
+type PhiDetector struct {
+	capacity int
+	cdf      PhiCDF
+	window   Sample
+
+	last    time.Time
+	hasLast bool
+}
+
+// NewPhiDetector returns a PhiDetector that models inter-arrival
+// times using cdf, over a sliding window of the last capacity
+// heartbeat intervals. If capacity <= 0, a default window size of
+// 1000 is used.
+func NewPhiDetector(capacity int, cdf PhiCDF) *PhiDetector {
+	if capacity <= 0 {
+		capacity = defaultPhiWindow
+	}
+	return &PhiDetector{
+		capacity: capacity,
+		cdf:      cdf,
+		window:   Sample{Xs: make([]float64, 0, capacity)},
+	}
+}
+
+// Heartbeat records an arrival at time now. The first call only
+// establishes a baseline; it takes a second call to record an
+// inter-arrival interval.
+func (d *PhiDetector) Heartbeat(now time.Time) {
+	if d.hasLast {
+		d.push(now.Sub(d.last).Seconds())
+	}
+	d.last = now
+	d.hasLast = true
+}
+
+// push records interval in the window, evicting the oldest interval
+// if the window is already at its configured capacity. It keeps the
+// window's backing array fixed at d.capacity rather than relying on
+// append's growth curve, so the window stays bounded at exactly
+// d.capacity regardless of how many heartbeats have been recorded.
+func (d *PhiDetector) push(interval float64) {
+	if len(d.window.Xs) < d.capacity {
+		d.window.Xs = append(d.window.Xs, interval)
+	} else {
+		copy(d.window.Xs, d.window.Xs[1:])
+		d.window.Xs[len(d.window.Xs)-1] = interval
+	}
+	d.window.Sorted = false
+	d.window.CumWeights = nil
+}
+
+// Phi returns the current suspicion level given that the last
+// heartbeat was observed at d's last Heartbeat call and now is the
+// current time: -log10(1 - F(elapsed)), where F is the CDF of the
+// window of past inter-arrival times and elapsed is the time since
+// the last heartbeat. Phi returns 0 until there is at least one
+// recorded inter-arrival interval.
+func (d *PhiDetector) Phi(now time.Time) float64 {
+	if !d.hasLast || len(d.window.Xs) == 0 {
+		return 0
+	}
+	elapsed := now.Sub(d.last).Seconds()
+
+	var f float64
+	switch d.cdf {
+	case PhiEmpiricalCDF:
+		f = d.empiricalCDF(elapsed)
+	default:
+		f = d.normalCDF(elapsed)
+	}
+	return -math.Log10(1 - f)
+}
+
+// normalCDF evaluates the CDF of a normal distribution fit to the
+// window's mean and standard deviation.
+func (d *PhiDetector) normalCDF(x float64) float64 {
+	mu, sigma := d.window.Mean(), d.window.StdDev()
+	if !(sigma > 0) {
+		// Too little data, or a window with no spread: treat
+		// arrivals up to the one known interval as certain, and
+		// anything beyond it as certain failure.
+		if x <= mu {
+			return 0
+		}
+		return 1
+	}
+	return normalCDF((x - mu) / sigma)
+}
+
+// empiricalCDF evaluates the fraction of the window's inter-arrival
+// times that are strictly less than x, via a binary search on the
+// sorted window.
+func (d *PhiDetector) empiricalCDF(x float64) float64 {
+	if !d.window.Sorted {
+		d.window.Sort()
+	}
+	i := sort.SearchFloat64s(d.window.Xs, x)
+	return float64(i) / float64(len(d.window.Xs))
+}
+
+// Suspect reports whether phi at now exceeds DefaultSuspicionThreshold.
+func (d *PhiDetector) Suspect(now time.Time) bool {
+	return d.IsSuspect(now, DefaultSuspicionThreshold)
+}
+
+// IsSuspect reports whether phi at now exceeds threshold.
+func (d *PhiDetector) IsSuspect(now time.Time, threshold float64) bool {
+	return d.Phi(now) > threshold
+}
@@ -0,0 +1,90 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stats
+
+import (
+	"testing"
+	"time"
+)
+
+// TestPhiDetectorWindowCapacity is a regression test for a bug where
+// push compared len(d.window.Xs) against cap(d.window.Xs) rather
+// than the detector's configured capacity, letting the window grow
+// past it once append reallocated a larger backing array.
+func TestPhiDetectorWindowCapacity(t *testing.T) {
+	const capacity = 10
+	d := NewPhiDetector(capacity, PhiNormalCDF)
+
+	now := time.Unix(0, 0)
+	for i := 0; i < capacity*3; i++ {
+		now = now.Add(time.Second)
+		d.Heartbeat(now)
+	}
+
+	if len(d.window.Xs) != capacity {
+		t.Errorf("len(window.Xs) = %v, want %v", len(d.window.Xs), capacity)
+	}
+}
+
+func TestPhiDetectorDefaultCapacity(t *testing.T) {
+	d := NewPhiDetector(0, PhiNormalCDF)
+	if d.capacity != defaultPhiWindow {
+		t.Errorf("capacity = %v, want %v", d.capacity, defaultPhiWindow)
+	}
+}
+
+func TestPhiZeroBeforeFirstInterval(t *testing.T) {
+	d := NewPhiDetector(10, PhiNormalCDF)
+	now := time.Unix(0, 0)
+	if got := d.Phi(now); got != 0 {
+		t.Errorf("Phi() before any Heartbeat = %v, want 0", got)
+	}
+	d.Heartbeat(now)
+	if got := d.Phi(now); got != 0 {
+		t.Errorf("Phi() after a single Heartbeat = %v, want 0", got)
+	}
+}
+
+func TestPhiIncreasesWithElapsedTime(t *testing.T) {
+	for _, cdf := range []PhiCDF{PhiNormalCDF, PhiEmpiricalCDF} {
+		d := NewPhiDetector(100, cdf)
+		now := time.Unix(0, 0)
+		// Establish a steady one-second heartbeat rhythm.
+		for i := 0; i < 50; i++ {
+			now = now.Add(time.Second)
+			d.Heartbeat(now)
+		}
+
+		var last float64
+		for _, elapsed := range []time.Duration{
+			time.Second, 2 * time.Second, 5 * time.Second, 10 * time.Second,
+		} {
+			phi := d.Phi(now.Add(elapsed))
+			if phi < last {
+				t.Errorf("cdf=%v: Phi at elapsed=%v = %v, want >= previous %v", cdf, elapsed, phi, last)
+			}
+			last = phi
+		}
+	}
+}
+
+func TestPhiSuspectThreshold(t *testing.T) {
+	d := NewPhiDetector(50, PhiNormalCDF)
+	now := time.Unix(0, 0)
+	for i := 0; i < 50; i++ {
+		now = now.Add(time.Second)
+		d.Heartbeat(now)
+	}
+
+	if d.Suspect(now.Add(time.Second)) {
+		t.Errorf("Suspect() right on schedule = true, want false")
+	}
+	if !d.Suspect(now.Add(10000 * time.Second)) {
+		t.Errorf("Suspect() long overdue = false, want true")
+	}
+	if !d.IsSuspect(now.Add(10000*time.Second), 0) {
+		t.Errorf("IsSuspect with threshold 0 = false, want true")
+	}
+}
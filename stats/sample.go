@@ -21,6 +21,34 @@ type Sample struct {
 
 	// Sorted indicates that Xs is sorted in ascending order.
 	Sorted bool
+
+	// CumWeights, if non-nil, is the cumulative sum of Weights:
+	// CumWeights[i] is the sum of Weights[0:i+1]. It enables
+	// Percentile to do an O(log n) lookup instead of an O(n)
+	// scan, but is only meaningful when Xs is sorted, so it is
+	// built (and rebuilt) by Sort and BuildCumWeights rather
+	// than maintained incrementally.
+	CumWeights []float64
+}
+
+// BuildCumWeights populates s.CumWeights from s.Weights and returns
+// s. It is a no-op if s.Weights is nil.
+//
+// This is normally unnecessary to call directly; Sort calls it
+// automatically on sorted, weighted Samples.
+func (s *Sample) BuildCumWeights() *Sample {
+	if s.Weights == nil {
+		s.CumWeights = nil
+		return s
+	}
+	cw := make([]float64, len(s.Weights))
+	sum := 0.0
+	for i, w := range s.Weights {
+		sum += w
+		cw[i] = sum
+	}
+	s.CumWeights = cw
+	return s
 }
 
 // Bounds returns the minimum and maximum values of xs.
@@ -148,8 +176,8 @@ func (s Sample) Mean() float64 {
 	return m
 }
 
-// StdDev returns the sample standard deviation of xs.
-func StdDev(xs []float64) float64 {
+// Variance returns the sample variance of xs.
+func Variance(xs []float64) float64 {
 	if len(xs) == 0 {
 		return math.NaN()
 	}
@@ -158,21 +186,103 @@ func StdDev(xs []float64) float64 {
 	// more numerically stable than the standard two-pass formula.
 	A, Q, k := 0.0, 0.0, 0
 	for _, x := range xs {
+		k++
 		Anext := A + (x-A)/float64(k)
 		Q += (x - A) * (x - Anext)
 		A = Anext
-		k++
 	}
-	return math.Sqrt(Q / float64(k-1))
+	return Q / float64(k-1)
+}
+
+// StdDev returns the sample standard deviation of xs.
+func StdDev(xs []float64) float64 {
+	return math.Sqrt(Variance(xs))
+}
+
+// VarianceKind selects which weighted variance estimator
+// Sample.WeightedVariance computes.
+type VarianceKind int
+
+const (
+	// FrequencyWeighted treats weights as integer frequencies
+	// (the number of times each value was observed) and
+	// computes the variance of the implied unweighted sample.
+	FrequencyWeighted VarianceKind = iota
+
+	// ReliabilityWeighted treats weights as a measure of the
+	// reliability of each observation (for example, normalized
+	// weights summing to 1) and applies Bessel's correction
+	// accordingly.
+	ReliabilityWeighted
+)
+
+// WeightedVariance returns the weighted sample variance of the
+// Sample using D.H.D. West's (1979) numerically stable incremental
+// algorithm. Points with zero weight are skipped. It returns NaN if
+// there are fewer than two effective (non-zero-weight) observations,
+// or if kind's normalizing denominator isn't positive: FrequencyWeighted
+// requires a total weight W > 1 (i.e. weights that are literal or
+// scaled counts, not weights normalized to sum to 1 or less, for
+// which ReliabilityWeighted is the appropriate kind).
+//
+// If the Sample is unweighted, this gives the same result as
+// Variance(s.Xs).
+func (s Sample) WeightedVariance(kind VarianceKind) float64 {
+	if s.Weights == nil {
+		return Variance(s.Xs)
+	}
+
+	var W, M, T, sumWSq float64
+	var n int
+	for i, x := range s.Xs {
+		w := s.Weights[i]
+		if w == 0 {
+			continue
+		}
+		Wnext := W + w
+		Q := x - M
+		R := Q * w / Wnext
+		M += R
+		T += W * Q * R
+		W = Wnext
+		sumWSq += w * w
+		n++
+	}
+	if n < 2 {
+		return math.NaN()
+	}
+
+	var denom float64
+	switch kind {
+	case ReliabilityWeighted:
+		denom = W - sumWSq/W
+	default:
+		denom = W - 1
+	}
+	if denom <= 0 {
+		return math.NaN()
+	}
+	return T / denom
+}
+
+// Variance returns the (possibly weighted) sample variance of the
+// Sample, using frequency weighting if the Sample is weighted.
+//
+// Frequency weighting assumes Weights are (possibly scaled) counts,
+// so it's only meaningful when the total weight exceeds 1; if not
+// (for example, normalized weights summing to 1), this returns NaN.
+// Callers with such weights should call
+// s.WeightedVariance(ReliabilityWeighted) instead.
+func (s Sample) Variance() float64 {
+	if s.Weights == nil {
+		return Variance(s.Xs)
+	}
+	return s.WeightedVariance(FrequencyWeighted)
 }
 
 // StdDev returns the sample standard deviation of the Sample.
 func (s Sample) StdDev() float64 {
-	if len(s.Xs) == 0 || s.Weights == nil {
-		return StdDev(s.Xs)
-	}
-	// TODO(austin)
-	panic("Weighted StdDev not implemented")
+	return math.Sqrt(s.Variance())
 }
 
 // Percentile returns the pctileth value from the Sample.
@@ -180,7 +290,12 @@ func (s Sample) StdDev() float64 {
 // pctile will be capped to the range [0, 1].  If len(xs) == 0 or all
 // weights are 0, returns NaN.
 //
-// This is constant time if s.Sorted and s.Weights == nil.
+// This is constant time if s.Sorted and s.Weights == nil. If s.Weights
+// != nil, s.Sorted and s.CumWeights has been built (see
+// BuildCumWeights), this is O(log n). An unsorted, unweighted Sample
+// takes O(n) expected time; a sorted, weighted Sample without
+// CumWeights takes O(n); an unsorted, weighted Sample is copied and
+// sorted first, taking O(n log n).
 func (s Sample) Percentile(pctile float64) float64 {
 	if len(s.Xs) == 0 {
 		return math.NaN()
@@ -192,26 +307,47 @@ func (s Sample) Percentile(pctile float64) float64 {
 		return max
 	}
 
+	if s.Weights == nil {
+		if !s.Sorted {
+			xs := make([]float64, len(s.Xs))
+			copy(xs, s.Xs)
+			return Select(xs, int(pctile*float64(len(xs)-1)))
+		}
+		return s.Xs[int(pctile*float64(len(s.Xs)-1))]
+	}
+
 	if !s.Sorted {
-		// TODO(austin) Use select algorithm instead
 		s = *s.Copy().Sort()
 	}
+	if s.CumWeights == nil {
+		(&s).BuildCumWeights()
+	}
 
-	if s.Weights == nil {
-		return s.Xs[int(pctile*float64(len(s.Xs)-1))]
-	} else {
-		target := s.Weight() * pctile
-
-		// TODO(austin) If we had cumulative weights, we could
-		// do this in log time.
-		for i, weight := range s.Weights {
-			target -= weight
-			if target < 0 {
-				return s.Xs[i]
-			}
-		}
-		return s.Xs[len(s.Xs)-1]
+	target := s.CumWeights[len(s.CumWeights)-1] * pctile
+	i := sort.Search(len(s.CumWeights), func(i int) bool {
+		return s.CumWeights[i] > target
+	})
+	if i == len(s.CumWeights) {
+		i--
+	}
+	return s.Xs[i]
+}
+
+// Quantiles returns the ps[i]th values from the Sample, for each i,
+// equivalent to calling s.Percentile(ps[i]) for each i but sorting
+// (and, if weighted, building cumulative weights) only once.
+func (s Sample) Quantiles(ps []float64) []float64 {
+	if !s.Sorted {
+		s = *s.Copy().Sort()
 	}
+	if s.Weights != nil && s.CumWeights == nil {
+		(&s).BuildCumWeights()
+	}
+	out := make([]float64, len(ps))
+	for i, p := range ps {
+		out[i] = s.Percentile(p)
+	}
+	return out
 }
 
 // IQR returns the interquartile range of the Sample.
@@ -254,6 +390,9 @@ func (s *Sample) Sort() *Sample {
 		sort.Sort(&sampleSorter{s.Xs, s.Weights})
 	}
 	s.Sorted = true
+	if s.Weights != nil {
+		s.BuildCumWeights()
+	}
 	return s
 }
 
@@ -271,5 +410,5 @@ func (s Sample) Copy() *Sample {
 		copy(weights, s.Weights)
 	}
 
-	return &Sample{xs, weights, s.Sorted}
+	return &Sample{xs, weights, s.Sorted, nil}
 }
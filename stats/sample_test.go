@@ -0,0 +1,71 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWeightedVarianceAllEqualWeights(t *testing.T) {
+	xs := []float64{2, 4, 4, 4, 5, 5, 7, 9}
+	weights := make([]float64, len(xs))
+	for i := range weights {
+		weights[i] = 1
+	}
+	s := Sample{Xs: xs, Weights: weights}
+
+	got := s.Variance()
+	want := Variance(xs)
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("Variance() = %v, want %v (unweighted formula)", got, want)
+	}
+	if got := s.StdDev(); math.Abs(got-math.Sqrt(want)) > 1e-9 {
+		t.Errorf("StdDev() = %v, want %v", got, math.Sqrt(want))
+	}
+}
+
+func TestWeightedVarianceZeroWeightSkipped(t *testing.T) {
+	s := Sample{Xs: []float64{1, 100, 2, 3}, Weights: []float64{1, 0, 1, 1}}
+	got := s.Variance()
+	want := Variance([]float64{1, 2, 3})
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("Variance() = %v, want %v", got, want)
+	}
+}
+
+func TestWeightedVarianceTooFewEffectiveObservations(t *testing.T) {
+	s := Sample{Xs: []float64{1, 2, 3}, Weights: []float64{1, 0, 0}}
+	if got := s.Variance(); !math.IsNaN(got) {
+		t.Errorf("Variance() = %v, want NaN", got)
+	}
+}
+
+// Frequency-weighted variance assumes weights are (scaled) counts,
+// so a total weight <= 1 has no valid Bessel correction and must
+// not silently produce a negative or infinite variance.
+func TestFrequencyWeightedVarianceRejectsSmallTotalWeight(t *testing.T) {
+	cases := []Sample{
+		{Xs: []float64{1, 5}, Weights: []float64{0.3, 0.3}}, // W = 0.6
+		{Xs: []float64{1, 5}, Weights: []float64{0.5, 0.5}}, // W = 1
+	}
+	for _, s := range cases {
+		if got := s.Variance(); !math.IsNaN(got) {
+			t.Errorf("Variance() for %+v = %v, want NaN", s, got)
+		}
+		if got := s.StdDev(); !math.IsNaN(got) {
+			t.Errorf("StdDev() for %+v = %v, want NaN", s, got)
+		}
+	}
+}
+
+func TestReliabilityWeightedVarianceNormalizedWeights(t *testing.T) {
+	s := Sample{Xs: []float64{1, 2, 3, 4}, Weights: []float64{0.25, 0.25, 0.25, 0.25}}
+	got := s.WeightedVariance(ReliabilityWeighted)
+	want := Variance(s.Xs)
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("WeightedVariance(ReliabilityWeighted) = %v, want %v", got, want)
+	}
+}
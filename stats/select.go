@@ -0,0 +1,157 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stats
+
+import (
+	"container/heap"
+	"math/bits"
+)
+
+// Select partitions xs in place and returns the kth smallest value
+// (0-indexed), equivalent to xs[k] after a full sort but without
+// paying for one.
+//
+// Select uses a median-of-medians pivot selection, giving O(n)
+// expected and O(n log n) worst-case time. As a safety net against
+// adversarial inputs defeating the pivot selection, Select falls
+// back to a heap-based selection once the recursion depth exceeds a
+// bound proportional to log2(len(xs)), mirroring the introsort
+// strategy used by Go's own sort package.
+//
+// Select panics if k is not in [0, len(xs)).
+func Select(xs []float64, k int) float64 {
+	if k < 0 || k >= len(xs) {
+		panic("stats: k is out of range")
+	}
+	depthLimit := 2 * bits.Len(uint(len(xs)))
+	return xs[selectIndex(xs, 0, len(xs)-1, k, depthLimit)]
+}
+
+// selectIndex returns the index within [lo, hi] at which the kth
+// smallest element of xs (k is an absolute index, not relative to
+// lo) will land, reordering xs[lo:hi+1] as a side effect.
+func selectIndex(xs []float64, lo, hi, k, depthLimit int) int {
+	for {
+		if lo == hi {
+			return lo
+		}
+		if depthLimit <= 0 {
+			return heapselectIndex(xs, lo, hi, k)
+		}
+		depthLimit--
+		pivotIndex := medianOfMediansPivot(xs, lo, hi, depthLimit)
+		eqLo, eqHi := partition3(xs, lo, hi, pivotIndex)
+		switch {
+		case k < eqLo:
+			hi = eqLo - 1
+		case k >= eqHi:
+			lo = eqHi
+		default:
+			// k falls within [eqLo, eqHi), which all holds the
+			// pivot value.
+			return k
+		}
+	}
+}
+
+// partition3 reorders xs[lo:hi+1] into three runs around the pivot
+// value xs[pivotIndex] — less-than, equal-to, and greater-than —
+// using Dijkstra's three-way partitioning, and returns [eqLo, eqHi),
+// the bounds of the equal-to-pivot run. Without this, a 2-way
+// partition degrades to O(n) per level (and O(n^2) overall) on
+// inputs with many duplicates of the pivot, which is common for
+// rounded timings and other real-world samples.
+func partition3(xs []float64, lo, hi, pivotIndex int) (eqLo, eqHi int) {
+	pivot := xs[pivotIndex]
+	xs[lo], xs[pivotIndex] = xs[pivotIndex], xs[lo]
+
+	lt, gt := lo, hi
+	i := lo + 1
+	for i <= gt {
+		switch {
+		case xs[i] < pivot:
+			xs[lt], xs[i] = xs[i], xs[lt]
+			lt++
+			i++
+		case xs[i] > pivot:
+			xs[gt], xs[i] = xs[i], xs[gt]
+			gt--
+		default:
+			i++
+		}
+	}
+	return lt, gt + 1
+}
+
+// medianOfMediansPivot returns the index of a good pivot for
+// xs[lo:hi+1] using the median-of-medians algorithm: xs is split
+// into groups of 5, each group's median is moved to the front of
+// the range, and the median of those medians is selected
+// recursively.
+func medianOfMediansPivot(xs []float64, lo, hi, depthLimit int) int {
+	n := hi - lo + 1
+	if n <= 5 {
+		insertionSort(xs, lo, hi)
+		return lo + n/2
+	}
+
+	numMedians := 0
+	for i := lo; i <= hi; i += 5 {
+		subHi := i + 4
+		if subHi > hi {
+			subHi = hi
+		}
+		insertionSort(xs, i, subHi)
+		medianIndex := i + (subHi-i)/2
+		xs[lo+numMedians], xs[medianIndex] = xs[medianIndex], xs[lo+numMedians]
+		numMedians++
+	}
+	return selectIndex(xs, lo, lo+numMedians-1, lo+numMedians/2, depthLimit)
+}
+
+func insertionSort(xs []float64, lo, hi int) {
+	for i := lo + 1; i <= hi; i++ {
+		v := xs[i]
+		j := i - 1
+		for j >= lo && xs[j] > v {
+			xs[j+1] = xs[j]
+			j--
+		}
+		xs[j+1] = v
+	}
+}
+
+// heapselectIndex is the worst-case fallback for selectIndex. It
+// finds the kth smallest element of xs[lo:hi+1] (k is an absolute
+// index) using a bounded max-heap of the k-lo+1 smallest elements
+// seen so far, taking O(n log k) time, writes that value into
+// xs[k] and returns k.
+func heapselectIndex(xs []float64, lo, hi, k int) int {
+	size := k - lo + 1
+	h := make(float64Heap, 0, size)
+	for i := lo; i <= hi; i++ {
+		heap.Push(&h, xs[i])
+		if h.Len() > size {
+			heap.Pop(&h)
+		}
+	}
+	xs[k] = h[0]
+	return k
+}
+
+// float64Heap is a max-heap of float64s.
+type float64Heap []float64
+
+func (h float64Heap) Len() int            { return len(h) }
+func (h float64Heap) Less(i, j int) bool  { return h[i] > h[j] }
+func (h float64Heap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *float64Heap) Push(x interface{}) { *h = append(*h, x.(float64)) }
+func (h *float64Heap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	v := old[n-1]
+	*h = old[:n-1]
+	return v
+}
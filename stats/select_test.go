@@ -0,0 +1,67 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stats
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestSelect(t *testing.T) {
+	sizes := []int{1, 2, 3, 5, 10, 37, 100, 500}
+	rng := rand.New(rand.NewSource(1))
+	for _, n := range sizes {
+		for trial := 0; trial < 5; trial++ {
+			xs := make([]float64, n)
+			for i := range xs {
+				// Heavy duplication: only a handful of distinct values.
+				xs[i] = float64(rng.Intn(5))
+			}
+			sorted := append([]float64(nil), xs...)
+			sort.Float64s(sorted)
+
+			for _, k := range []int{0, n / 2, n - 1} {
+				got := Select(append([]float64(nil), xs...), k)
+				want := sorted[k]
+				if got != want {
+					t.Fatalf("n=%d k=%d: Select = %v, want %v", n, k, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestSelectRandomValues(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for trial := 0; trial < 20; trial++ {
+		n := 1 + rng.Intn(300)
+		xs := make([]float64, n)
+		for i := range xs {
+			xs[i] = rng.Float64() * 1000
+		}
+		sorted := append([]float64(nil), xs...)
+		sort.Float64s(sorted)
+
+		k := rng.Intn(n)
+		got := Select(append([]float64(nil), xs...), k)
+		if got != sorted[k] {
+			t.Fatalf("n=%d k=%d: Select = %v, want %v", n, k, got, sorted[k])
+		}
+	}
+}
+
+func TestSelectPanicsOnOutOfRange(t *testing.T) {
+	for _, k := range []int{-1, 3} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Select(xs, %d) did not panic", k)
+				}
+			}()
+			Select([]float64{1, 2, 3}, k)
+		}()
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stats
+
+import "math"
+
+// Summary carries a collection of common descriptive statistics for
+// a Sample, as computed by Sample.Summary.
+type Summary struct {
+	N int
+
+	Min, Max float64
+	Mean     float64
+	Median   float64
+	StdDev   float64
+
+	// Q1 and Q3 are the first and third quartiles (the 25th and
+	// 75th percentiles) and IQR is their difference.
+	Q1, Q3, IQR float64
+
+	// GeoMean is the geometric mean, exp(mean(log(Xs))). It is
+	// NaN if any sample value is non-positive.
+	GeoMean float64
+
+	// HarmonicMean is the harmonic mean. It is NaN if any
+	// sample value is zero.
+	HarmonicMean float64
+
+	// TrimmedMean is the mean of the Sample with the lowest and
+	// highest 5% of values (by weight) removed from each tail.
+	TrimmedMean float64
+}
+
+// Summary computes a Summary of the Sample. Where possible, the
+// underlying statistics are computed in a single pass over the
+// (sorted) data.
+func (s Sample) Summary() Summary {
+	if !s.Sorted {
+		s = *s.Copy().Sort()
+	}
+
+	min, max := s.Bounds()
+	q1, q3 := s.Percentile(0.25), s.Percentile(0.75)
+
+	var sumLogW, sumInvW, wsum float64
+	geoOK, harmOK := true, true
+	for i, x := range s.Xs {
+		w := 1.0
+		if s.Weights != nil {
+			w = s.Weights[i]
+		}
+		if w == 0 {
+			continue
+		}
+		wsum += w
+		if x <= 0 {
+			geoOK = false
+		} else {
+			sumLogW += w * math.Log(x)
+		}
+		if x == 0 {
+			harmOK = false
+		} else {
+			sumInvW += w / x
+		}
+	}
+
+	geoMean, harmonicMean := math.NaN(), math.NaN()
+	if geoOK && wsum > 0 {
+		geoMean = math.Exp(sumLogW / wsum)
+	}
+	if harmOK && wsum > 0 {
+		harmonicMean = wsum / sumInvW
+	}
+
+	return Summary{
+		N:            len(s.Xs),
+		Min:          min,
+		Max:          max,
+		Mean:         s.Mean(),
+		Median:       s.Percentile(0.5),
+		StdDev:       s.StdDev(),
+		Q1:           q1,
+		Q3:           q3,
+		IQR:          q3 - q1,
+		GeoMean:      geoMean,
+		HarmonicMean: harmonicMean,
+		TrimmedMean:  s.Trim(0.05, 0.05).Mean(),
+	}
+}
+
+// RemoveOutliers returns a new Sample with values outside
+// [Q1-1.5*IQR, Q3+1.5*IQR] removed, using the IQR rule benchstat
+// applies before comparing benchmark distributions. Weights, if
+// any, are carried over unchanged for the values that remain.
+func (s Sample) RemoveOutliers() *Sample {
+	if !s.Sorted {
+		s = *s.Copy().Sort()
+	}
+	q1, q3 := s.Percentile(0.25), s.Percentile(0.75)
+	iqr := q3 - q1
+	lo, hi := q1-1.5*iqr, q3+1.5*iqr
+
+	xs := make([]float64, 0, len(s.Xs))
+	var weights []float64
+	if s.Weights != nil {
+		weights = make([]float64, 0, len(s.Weights))
+	}
+	for i, x := range s.Xs {
+		if x < lo || x > hi {
+			continue
+		}
+		xs = append(xs, x)
+		if s.Weights != nil {
+			weights = append(weights, s.Weights[i])
+		}
+	}
+	return &Sample{Xs: xs, Weights: weights, Sorted: true}
+}
+
+// Trim returns a new Sample with the lowest lo and highest hi
+// fraction (by weight, if the Sample is weighted) of values removed
+// from each tail. lo and hi must each be in [0, 1] and lo+hi <= 1.
+func (s Sample) Trim(lo, hi float64) *Sample {
+	if !s.Sorted {
+		s = *s.Copy().Sort()
+	}
+	n := len(s.Xs)
+	if n == 0 {
+		return s.Copy()
+	}
+
+	if s.Weights == nil {
+		start := int(float64(n) * lo)
+		end := n - int(float64(n)*hi)
+		if end < start {
+			end = start
+		}
+		xs := make([]float64, end-start)
+		copy(xs, s.Xs[start:end])
+		return &Sample{Xs: xs, Sorted: true}
+	}
+
+	if s.CumWeights == nil {
+		(&s).BuildCumWeights()
+	}
+	total := s.Weight()
+	loW, hiW := total*lo, total*(1-hi)
+
+	xs := make([]float64, 0, n)
+	weights := make([]float64, 0, n)
+	for i, x := range s.Xs {
+		if s.CumWeights[i] <= loW || s.CumWeights[i]-s.Weights[i] >= hiW {
+			continue
+		}
+		xs = append(xs, x)
+		weights = append(weights, s.Weights[i])
+	}
+	return &Sample{Xs: xs, Weights: weights, Sorted: true}
+}
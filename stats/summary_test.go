@@ -0,0 +1,173 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSummaryGeoAndHarmonicMean(t *testing.T) {
+	xs := []float64{1, 2, 4, 8}
+	s := Sample{Xs: xs}
+	sum := s.Summary()
+
+	// GeoMean = (1*2*4*8)^(1/4) = 64^0.25 = 2.8284...
+	wantGeo := math.Pow(1*2*4*8, 0.25)
+	if math.Abs(sum.GeoMean-wantGeo) > 1e-9 {
+		t.Errorf("GeoMean = %v, want %v", sum.GeoMean, wantGeo)
+	}
+
+	// HarmonicMean = 4 / (1/1 + 1/2 + 1/4 + 1/8) = 4 / 1.875
+	wantHarm := 4 / (1 + 1.0/2 + 1.0/4 + 1.0/8)
+	if math.Abs(sum.HarmonicMean-wantHarm) > 1e-9 {
+		t.Errorf("HarmonicMean = %v, want %v", sum.HarmonicMean, wantHarm)
+	}
+}
+
+func TestSummaryGeoMeanNaNOnNonPositive(t *testing.T) {
+	s := Sample{Xs: []float64{1, 2, -1, 4}}
+	sum := s.Summary()
+	if !math.IsNaN(sum.GeoMean) {
+		t.Errorf("GeoMean = %v, want NaN for a sample containing a non-positive value", sum.GeoMean)
+	}
+	// Harmonic mean only guards against zero, not negative values.
+	if math.IsNaN(sum.HarmonicMean) {
+		t.Errorf("HarmonicMean = NaN, want a finite value (no zero in sample)")
+	}
+}
+
+func TestSummaryHarmonicMeanNaNOnZero(t *testing.T) {
+	s := Sample{Xs: []float64{1, 2, 0, 4}}
+	sum := s.Summary()
+	if !math.IsNaN(sum.HarmonicMean) {
+		t.Errorf("HarmonicMean = %v, want NaN for a sample containing zero", sum.HarmonicMean)
+	}
+	// Zero is also non-positive, so GeoMean must guard it too.
+	if !math.IsNaN(sum.GeoMean) {
+		t.Errorf("GeoMean = %v, want NaN for a sample containing zero", sum.GeoMean)
+	}
+}
+
+func TestSummaryWeightedGeoAndHarmonicMean(t *testing.T) {
+	// Weight 2, 1 is equivalent to the unweighted sample {2, 2, 8}.
+	s := Sample{Xs: []float64{2, 8}, Weights: []float64{2, 1}}
+	sum := s.Summary()
+
+	wantGeo := math.Pow(2*2*8, 1.0/3)
+	if math.Abs(sum.GeoMean-wantGeo) > 1e-9 {
+		t.Errorf("GeoMean = %v, want %v", sum.GeoMean, wantGeo)
+	}
+	wantHarm := 3 / (1.0/2 + 1.0/2 + 1.0/8)
+	if math.Abs(sum.HarmonicMean-wantHarm) > 1e-9 {
+		t.Errorf("HarmonicMean = %v, want %v", sum.HarmonicMean, wantHarm)
+	}
+}
+
+func TestRemoveOutliers(t *testing.T) {
+	// Q1=2, Q3=4 (interpolation-free with this Percentile
+	// implementation: index-based on a sorted 7-element slice),
+	// IQR=2, so the outlier fence is [2-3, 4+3] = [-1, 7]. 100 is
+	// the lone outlier.
+	xs := []float64{1, 2, 2, 3, 4, 4, 100}
+	s := Sample{Xs: xs}
+	q1, q3 := s.Percentile(0.25), s.Percentile(0.75)
+	iqr := q3 - q1
+	lo, hi := q1-1.5*iqr, q3+1.5*iqr
+
+	got := s.RemoveOutliers()
+	for _, x := range got.Xs {
+		if x < lo || x > hi {
+			t.Errorf("RemoveOutliers() kept out-of-fence value %v (fence [%v, %v])", x, lo, hi)
+		}
+	}
+	for _, x := range xs {
+		inFence := x >= lo && x <= hi
+		found := false
+		for _, g := range got.Xs {
+			if g == x {
+				found = true
+				break
+			}
+		}
+		if inFence && !found {
+			t.Errorf("RemoveOutliers() dropped in-fence value %v", x)
+		}
+	}
+	if len(got.Xs) != len(xs)-1 {
+		t.Errorf("RemoveOutliers() kept %d values, want %d", len(got.Xs), len(xs)-1)
+	}
+}
+
+func TestRemoveOutliersPreservesWeights(t *testing.T) {
+	s := Sample{Xs: []float64{1, 2, 2, 3, 4, 4, 100}, Weights: []float64{1, 1, 1, 1, 1, 1, 1}}
+	got := s.RemoveOutliers()
+	if len(got.Weights) != len(got.Xs) {
+		t.Fatalf("RemoveOutliers() len(Weights) = %d, want %d", len(got.Weights), len(got.Xs))
+	}
+	for _, w := range got.Weights {
+		if w != 1 {
+			t.Errorf("RemoveOutliers() weight = %v, want 1", w)
+		}
+	}
+}
+
+func TestTrimUnweighted(t *testing.T) {
+	xs := []float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	s := Sample{Xs: append([]float64(nil), xs...)}
+	// start = int(10*0.1) = 1, end = 10 - int(10*0.2) = 8.
+	got := s.Trim(0.1, 0.2)
+	want := []float64{1, 2, 3, 4, 5, 6, 7}
+	if len(got.Xs) != len(want) {
+		t.Fatalf("Trim(0.1, 0.2).Xs = %v, want %v", got.Xs, want)
+	}
+	for i := range want {
+		if got.Xs[i] != want[i] {
+			t.Fatalf("Trim(0.1, 0.2).Xs = %v, want %v", got.Xs, want)
+		}
+	}
+}
+
+func TestTrimWeightedBoundary(t *testing.T) {
+	// Cumulative weights: 1, 3, 12, 14, 15, for a total weight of 15.
+	// Trim(0.2, 0.2) sets loW=3, hiW=12, so the boundary points (the
+	// one whose CumWeights lands exactly on loW, and the one whose
+	// CumWeights-Weights lands exactly on hiW) pin down the <= / >=
+	// edge cases in Trim's weighted filter.
+	xs := []float64{1, 2, 3, 4, 5}
+	weights := []float64{1, 2, 9, 2, 1}
+	s := Sample{Xs: append([]float64(nil), xs...), Weights: append([]float64(nil), weights...)}
+
+	got := s.Trim(0.2, 0.2)
+
+	total := Sum(weights)
+	loW, hiW := total*0.2, total*0.8
+	cum := 0.0
+	var want []float64
+	for i, x := range xs {
+		cum += weights[i]
+		if cum <= loW || cum-weights[i] >= hiW {
+			continue
+		}
+		want = append(want, x)
+	}
+
+	if len(got.Xs) != len(want) {
+		t.Fatalf("Trim(0.2, 0.2).Xs = %v, want %v", got.Xs, want)
+	}
+	for i := range want {
+		if got.Xs[i] != want[i] {
+			t.Fatalf("Trim(0.2, 0.2).Xs = %v, want %v", got.Xs, want)
+		}
+	}
+}
+
+func TestTrimEmptySample(t *testing.T) {
+	s := Sample{}
+	got := s.Trim(0.1, 0.1)
+	if len(got.Xs) != 0 {
+		t.Errorf("Trim on empty Sample returned %v, want empty", got.Xs)
+	}
+}
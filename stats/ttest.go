@@ -0,0 +1,113 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stats
+
+import (
+	"errors"
+	"math"
+)
+
+// Alternative specifies the alternative hypothesis of a two-sample
+// test, in terms of a location parameter (mean, median, ...) of a
+// relative to b.
+type Alternative int
+
+const (
+	// TwoSided is the alternative hypothesis that a's location
+	// differs from b's.
+	TwoSided Alternative = iota
+
+	// Less is the alternative hypothesis that a's location is
+	// less than b's.
+	Less
+
+	// Greater is the alternative hypothesis that a's location is
+	// greater than b's.
+	Greater
+)
+
+// TTestOpts specifies options for TTest.
+type TTestOpts struct {
+	// Alternative specifies the alternative hypothesis to test
+	// against. The zero value is TwoSided.
+	Alternative Alternative
+}
+
+// TTestResult is the result of a TTest.
+type TTestResult struct {
+	N1, N2 int
+
+	// T is the t statistic for this test.
+	T float64
+
+	// DoF is the effective degrees of freedom, computed using
+	// the Welch-Satterthwaite equation.
+	DoF float64
+
+	// P is the p-value for this test, consistent with the
+	// alternative hypothesis requested in TTestOpts.
+	P float64
+
+	// EffectSize is Cohen's d: the difference between the
+	// sample means divided by the pooled standard deviation.
+	EffectSize float64
+}
+
+// TTest performs Welch's unequal-variance t-test of the null
+// hypothesis that a and b have the same population mean, against
+// the alternative hypothesis given in opts.
+//
+// TTest requires both a and b to have at least two observations.
+func TTest(a, b Sample, opts TTestOpts) (TTestResult, error) {
+	n1, n2 := len(a.Xs), len(b.Xs)
+	if n1 < 2 || n2 < 2 {
+		return TTestResult{}, errors.New("stats: samples must have at least two observations")
+	}
+
+	m1, m2 := a.Mean(), b.Mean()
+	v1, v2 := a.Variance(), b.Variance()
+	v1n, v2n := v1/float64(n1), v2/float64(n2)
+
+	se2 := v1n + v2n
+	se := math.Sqrt(se2)
+	t := (m1 - m2) / se
+
+	dof := se2 * se2 / (v1n*v1n/float64(n1-1) + v2n*v2n/float64(n2-1))
+
+	p := tTestP(t, dof, opts.Alternative)
+	pooledSD := math.Sqrt((v1 + v2) / 2)
+
+	return TTestResult{
+		N1:         n1,
+		N2:         n2,
+		T:          t,
+		DoF:        dof,
+		P:          p,
+		EffectSize: (m1 - m2) / pooledSD,
+	}, nil
+}
+
+// tTestP returns the p-value for a t statistic with dof degrees of
+// freedom under alt, using the regularized incomplete beta function
+// to evaluate the Student's t CDF.
+func tTestP(t, dof float64, alt Alternative) float64 {
+	x := dof / (dof + t*t)
+	pTwo := betainc(x, dof/2, 0.5)
+
+	switch alt {
+	case Less:
+		if t <= 0 {
+			return pTwo / 2
+		}
+		return 1 - pTwo/2
+	case Greater:
+		if t >= 0 {
+			return pTwo / 2
+		}
+		return 1 - pTwo/2
+	default:
+		return pTwo
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+// TestTTestTwoSided checks Welch's t-test against independently
+// computed reference values (direct evaluation of the Welch t
+// statistic, Welch-Satterthwaite DoF, and a numerical integration of
+// the Student's t tail probability) for two samples of unequal size
+// and variance.
+func TestTTestTwoSided(t *testing.T) {
+	lean := Sample{Xs: []float64{
+		9.21, 11.51, 12.79, 11.85, 9.97, 8.79, 9.69, 9.68, 9.19, 8.35,
+	}}
+	obese := Sample{Xs: []float64{
+		13.34, 10.99, 11.87, 13.02, 11.33, 11.33, 13.26, 11.62, 11.27, 10.81, 12.41, 12.65, 11.33,
+	}}
+
+	res, err := TTest(lean, obese, TTestOpts{})
+	if err != nil {
+		t.Fatalf("TTest: %v", err)
+	}
+	if math.Abs(res.T-(-3.522592829901273)) > 1e-6 {
+		t.Errorf("T = %v, want ~ -3.522592829901273", res.T)
+	}
+	if math.Abs(res.DoF-14.025580678225692) > 1e-6 {
+		t.Errorf("DoF = %v, want ~ 14.025580678225692", res.DoF)
+	}
+	if math.Abs(res.P-0.0033715330724957884) > 1e-6 {
+		t.Errorf("P = %v, want ~ 0.0033715330724957884", res.P)
+	}
+}
+
+func TestTTestOneSided(t *testing.T) {
+	a := Sample{Xs: []float64{1, 2, 3, 4, 5}}
+	b := Sample{Xs: []float64{10, 11, 12, 13, 14}}
+
+	two, err := TTest(a, b, TTestOpts{Alternative: TwoSided})
+	if err != nil {
+		t.Fatalf("TTest: %v", err)
+	}
+	less, err := TTest(a, b, TTestOpts{Alternative: Less})
+	if err != nil {
+		t.Fatalf("TTest: %v", err)
+	}
+	greater, err := TTest(a, b, TTestOpts{Alternative: Greater})
+	if err != nil {
+		t.Fatalf("TTest: %v", err)
+	}
+
+	// a's mean is much less than b's, so the one-sided alternative
+	// in the observed direction should have about half the
+	// two-sided p-value, and the opposite direction close to 1.
+	if math.Abs(less.P-two.P/2) > 1e-9 {
+		t.Errorf("Less.P = %v, want %v", less.P, two.P/2)
+	}
+	if math.Abs(greater.P-(1-two.P/2)) > 1e-9 {
+		t.Errorf("Greater.P = %v, want %v", greater.P, 1-two.P/2)
+	}
+}
+
+func TestTTestRequiresTwoObservations(t *testing.T) {
+	a := Sample{Xs: []float64{1}}
+	b := Sample{Xs: []float64{1, 2}}
+	if _, err := TTest(a, b, TTestOpts{}); err == nil {
+		t.Errorf("TTest with a 1-element sample did not return an error")
+	}
+}
+
+func TestTTestEffectSize(t *testing.T) {
+	a := Sample{Xs: []float64{1, 2, 3, 4, 5}}
+	b := Sample{Xs: []float64{1, 2, 3, 4, 5}}
+	res, err := TTest(a, b, TTestOpts{})
+	if err != nil {
+		t.Fatalf("TTest: %v", err)
+	}
+	if res.EffectSize != 0 {
+		t.Errorf("EffectSize = %v, want 0 for identical samples", res.EffectSize)
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stats
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// UTestResult is the result of a Mann-Whitney U test.
+type UTestResult struct {
+	N1, N2 int
+
+	// U is the Mann-Whitney U statistic for sample a (the
+	// number of pairs (x, y) with x from a, y from b, such that
+	// x > y, plus half the number of ties).
+	U float64
+
+	// P is the two-sided p-value of the test.
+	P float64
+
+	// EffectSize is the rank-biserial correlation,
+	// (U - U') / (N1*N2), where U' is the complementary
+	// statistic for sample b. It ranges over [-1, 1], with 0
+	// indicating the samples are drawn from the same
+	// distribution.
+	EffectSize float64
+}
+
+// exactUTestMaxN bounds the sample sizes for which UTest computes
+// an exact p-value; beyond this, and whenever there are ties, it
+// falls back to the normal approximation.
+const exactUTestMaxN = 25
+
+// UTest performs the Mann-Whitney U rank-sum test of the null
+// hypothesis that a and b are drawn from the same distribution,
+// against the two-sided alternative that they are not.
+//
+// The exact distribution of U is used when there are no ties and
+// both samples are small; otherwise a normal approximation with a
+// tie correction is used.
+//
+// UTest requires both a and b to be non-empty.
+func UTest(a, b Sample) (UTestResult, error) {
+	n1, n2 := len(a.Xs), len(b.Xs)
+	if n1 == 0 || n2 == 0 {
+		return UTestResult{}, errors.New("stats: samples must be non-empty")
+	}
+
+	type labeled struct {
+		x   float64
+		inA bool
+	}
+	n := n1 + n2
+	vals := make([]labeled, 0, n)
+	for _, x := range a.Xs {
+		vals = append(vals, labeled{x, true})
+	}
+	for _, x := range b.Xs {
+		vals = append(vals, labeled{x, false})
+	}
+	sort.Slice(vals, func(i, j int) bool { return vals[i].x < vals[j].x })
+
+	// Assign ranks, averaging over tied groups, and accumulate
+	// the tie correction term sum(t^3 - t) over tie-group sizes t.
+	ranks := make([]float64, n)
+	var tieTerm float64
+	for i := 0; i < n; {
+		j := i
+		for j+1 < n && vals[j+1].x == vals[i].x {
+			j++
+		}
+		avgRank := float64(i+j)/2 + 1
+		for k := i; k <= j; k++ {
+			ranks[k] = avgRank
+		}
+		t := float64(j - i + 1)
+		tieTerm += t*t*t - t
+		i = j + 1
+	}
+
+	var rankSumA float64
+	for i, v := range vals {
+		if v.inA {
+			rankSumA += ranks[i]
+		}
+	}
+	u1 := rankSumA - float64(n1*(n1+1))/2
+	u2 := float64(n1*n2) - u1
+
+	var p float64
+	if tieTerm == 0 && n1 <= exactUTestMaxN && n2 <= exactUTestMaxN {
+		p = uExactP(n1, n2, math.Min(u1, u2))
+	} else {
+		p = uNormalP(u1, n1, n2, tieTerm)
+	}
+
+	return UTestResult{
+		N1:         n1,
+		N2:         n2,
+		U:          u1,
+		P:          p,
+		EffectSize: (u1 - u2) / float64(n1*n2),
+	}, nil
+}
+
+// uNormalP returns the two-sided p-value for the Mann-Whitney U
+// statistic u1 using the normal approximation with continuity and
+// tie correction. tieTerm is sum(t^3 - t) over tie-group sizes t.
+func uNormalP(u1 float64, n1, n2 int, tieTerm float64) float64 {
+	N := float64(n1 + n2)
+	muU := float64(n1*n2) / 2
+	sigmaU2 := float64(n1*n2) / 12 * ((N + 1) - tieTerm/(N*(N-1)))
+	sigmaU := math.Sqrt(sigmaU2)
+	if sigmaU == 0 {
+		return 1
+	}
+
+	var z float64
+	if u1 > muU {
+		z = (u1 - 0.5 - muU) / sigmaU
+	} else {
+		z = (u1 + 0.5 - muU) / sigmaU
+	}
+	return math.Min(1, 2*(1-normalCDF(math.Abs(z))))
+}
+
+// uExactP returns the exact two-sided p-value for the smaller of
+// the two Mann-Whitney U statistics, given no ties, using the
+// recurrence of Mann & Whitney (1947) for the null distribution
+// of U.
+func uExactP(n1, n2 int, u float64) float64 {
+	uu := int(math.Round(u))
+
+	// counts[i][j] is the number of arrangements of i+j ranks
+	// achieving each value of U from 0 to i*j.
+	counts := make([][][]float64, n1+1)
+	for i := 0; i <= n1; i++ {
+		counts[i] = make([][]float64, n2+1)
+		for j := 0; j <= n2; j++ {
+			counts[i][j] = make([]float64, i*j+1)
+		}
+	}
+	counts[0][0][0] = 1
+	for i := 0; i <= n1; i++ {
+		for j := 0; j <= n2; j++ {
+			if i == 0 && j == 0 {
+				continue
+			}
+			for k := range counts[i][j] {
+				var total float64
+				if i > 0 && k-j >= 0 && k-j < len(counts[i-1][j]) {
+					total += counts[i-1][j][k-j]
+				}
+				if j > 0 && k < len(counts[i][j-1]) {
+					total += counts[i][j-1][k]
+				}
+				counts[i][j][k] = total
+			}
+		}
+	}
+
+	var totalCount, cumCount float64
+	dist := counts[n1][n2]
+	for k, c := range dist {
+		totalCount += c
+		if k <= uu {
+			cumCount += c
+		}
+	}
+	return math.Min(1, 2*cumCount/totalCount)
+}
@@ -0,0 +1,113 @@
+// Copyright 2015 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+// TestUTestExact checks the exact branch (small samples, no ties)
+// against a p-value computed by brute-force enumeration of all
+// C(n1+n2, n1) = 126 rank assignments, independent of the package's
+// DP recurrence: for n1=4, n2=5, u1=3, that enumeration gives
+// p = 2*7/126 = 0.111111....
+func TestUTestExact(t *testing.T) {
+	// Ranks 1..9. a occupies ranks {1, 2, 3, 7}, giving
+	// u1 = (1+2+3+7) - 4*5/2 = 3.
+	a := Sample{Xs: []float64{1, 2, 3, 7}}
+	b := Sample{Xs: []float64{4, 5, 6, 8, 9}}
+
+	res, err := UTest(a, b)
+	if err != nil {
+		t.Fatalf("UTest: %v", err)
+	}
+	if res.U != 3 {
+		t.Fatalf("U = %v, want 3 (test setup assumption violated)", res.U)
+	}
+	want := 0.1111111111111111
+	if math.Abs(res.P-want) > 1e-9 {
+		t.Errorf("P = %v, want %v", res.P, want)
+	}
+	wantEffect := (3.0 - (4*5 - 3.0)) / (4 * 5)
+	if math.Abs(res.EffectSize-wantEffect) > 1e-9 {
+		t.Errorf("EffectSize = %v, want %v", res.EffectSize, wantEffect)
+	}
+}
+
+func TestUTestAllValuesTiedZeroEffectAndUnitP(t *testing.T) {
+	// Every value is the same, so there's a single tie group
+	// spanning the whole combined sample: the tie-corrected
+	// variance is exactly 0, and uNormalP must special-case that
+	// rather than dividing by it.
+	a := Sample{Xs: []float64{3, 3, 3, 3, 3}}
+	b := Sample{Xs: []float64{3, 3, 3, 3, 3}}
+	res, err := UTest(a, b)
+	if err != nil {
+		t.Fatalf("UTest: %v", err)
+	}
+	if res.EffectSize != 0 {
+		t.Errorf("EffectSize = %v, want 0 for identical samples", res.EffectSize)
+	}
+	if res.P != 1 {
+		t.Errorf("P = %v, want 1 when the tie-corrected variance is 0", res.P)
+	}
+}
+
+// TestUTestNormalApproxWithTies checks the normal-approximation
+// branch (triggered by ties) against a p-value computed by
+// reimplementing the documented formula (tie-corrected variance,
+// continuity-corrected z) independently of uNormalP.
+func TestUTestNormalApproxWithTies(t *testing.T) {
+	a := Sample{Xs: []float64{1, 2, 2, 3, 5}}
+	b := Sample{Xs: []float64{2, 3, 3, 4, 6}}
+
+	res, err := UTest(a, b)
+	if err != nil {
+		t.Fatalf("UTest: %v", err)
+	}
+
+	n1, n2 := 5, 5
+	N := float64(n1 + n2)
+	// Ranks (1-indexed, averaged over ties) of the combined sorted
+	// sample 1,2,2,2,3,3,3,4,5,6: value 2 (x3: two from a, one
+	// from b, ranks 2-4 -> avg 3), value 3 (x3: one from a, two
+	// from b, ranks 5-7 -> avg 6).
+	// a: 1(rank 1), 2(rank 3), 2(rank 3), 3(rank 6), 5(rank 9)
+	rankSumA := 1.0 + 3 + 3 + 6 + 9
+	u1 := rankSumA - float64(n1*(n1+1))/2
+	u2 := float64(n1*n2) - u1
+
+	tieTerm := (3.0*3*3 - 3) + (3.0*3*3 - 3) // two triple ties
+	muU := float64(n1*n2) / 2
+	sigmaU2 := float64(n1*n2) / 12 * ((N + 1) - tieTerm/(N*(N-1)))
+	sigmaU := math.Sqrt(sigmaU2)
+	var z float64
+	if u1 > muU {
+		z = (u1 - 0.5 - muU) / sigmaU
+	} else {
+		z = (u1 + 0.5 - muU) / sigmaU
+	}
+	wantP := math.Min(1, 2*(1-normalCDF(math.Abs(z))))
+
+	if res.U != u1 {
+		t.Fatalf("U = %v, want %v (test setup assumption violated)", res.U, u1)
+	}
+	if math.Abs(res.P-wantP) > 1e-9 {
+		t.Errorf("P = %v, want %v", res.P, wantP)
+	}
+	wantEffect := (u1 - u2) / float64(n1*n2)
+	if math.Abs(res.EffectSize-wantEffect) > 1e-9 {
+		t.Errorf("EffectSize = %v, want %v", res.EffectSize, wantEffect)
+	}
+}
+
+func TestUTestRequiresNonEmptySamples(t *testing.T) {
+	a := Sample{Xs: []float64{}}
+	b := Sample{Xs: []float64{1}}
+	if _, err := UTest(a, b); err == nil {
+		t.Errorf("UTest with an empty sample did not return an error")
+	}
+}